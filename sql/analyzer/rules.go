@@ -0,0 +1,32 @@
+// Package analyzer holds the rules the engine runs over a resolved query
+// plan before executing it.
+package analyzer
+
+import (
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// Rule is a single plan-rewriting pass. It receives a fully resolved node
+// and returns the (possibly rewritten) node to run in its place.
+type Rule func(sql.Node) (sql.Node, error)
+
+// DefaultRules is the list of rules applied, in order, to every resolved
+// query plan.
+var DefaultRules = []Rule{
+	plan.OptimizeCrossJoins,
+}
+
+// Apply runs every rule in DefaultRules over node in order, feeding each
+// rule's output into the next.
+func Apply(node sql.Node) (sql.Node, error) {
+	var err error
+	for _, rule := range DefaultRules {
+		node, err = rule(node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
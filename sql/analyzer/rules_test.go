@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func TestDefaultRulesIncludesOptimizeCrossJoins(t *testing.T) {
+	require := require.New(t)
+
+	want := reflect.ValueOf(plan.OptimizeCrossJoins).Pointer()
+
+	var found bool
+	for _, rule := range DefaultRules {
+		if reflect.ValueOf(rule).Pointer() == want {
+			found = true
+			break
+		}
+	}
+
+	require.True(found, "expected plan.OptimizeCrossJoins to be registered in DefaultRules")
+}
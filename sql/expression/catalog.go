@@ -0,0 +1,89 @@
+package expression
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+// Defaults is the function registry containing every built-in function
+// this package defines. The parser's function-call resolution looks
+// functions up here by name.
+var Defaults = sql.NewFunctionRegistry()
+
+func init() {
+	Defaults.Register("substring", func(args ...sql.Expression) (sql.Expression, error) {
+		return NewSubstring(args...)
+	})
+	Defaults.Register("is_binary", NewIsBinary)
+	Defaults.Register("substring_index", fixed3(NewSubstringIndex))
+	Defaults.Register("left", fixed2(NewLeft))
+	Defaults.Register("right", fixed2(NewRight))
+	Defaults.Register("lpad", fixed3(NewLPad))
+	Defaults.Register("rpad", fixed3(NewRPad))
+	Defaults.Register("replace", fixed3(NewReplace))
+	Defaults.Register("locate", NewLocate)
+	Defaults.Register("instr", fixed2(NewInstr))
+	Defaults.Register("trim", fixed1(NewTrim))
+	Defaults.Register("ltrim", fixed1(NewLTrim))
+	Defaults.Register("rtrim", fixed1(NewRTrim))
+	Defaults.Register("reverse", fixed1(NewReverse))
+	Defaults.Register("repeat", fixed2(NewRepeat))
+	Defaults.Register("concat_ws", NewConcatWithSeparator)
+	Defaults.Register("char_length", fixed1(NewCharLength))
+
+	Defaults.Register("year", fixed1(NewYear))
+	Defaults.Register("month", fixed1(NewMonth))
+	Defaults.Register("day", fixed1(NewDay))
+	Defaults.Register("dayofweek", fixed1(NewDayOfWeek))
+	Defaults.Register("dayofyear", fixed1(NewDayOfYear))
+	Defaults.Register("hour", fixed1(NewHour))
+	Defaults.Register("minute", fixed1(NewMinute))
+	Defaults.Register("second", fixed1(NewSecond))
+	Defaults.Register("weekday", fixed1(NewWeekday))
+	Defaults.Register("date_format", fixed2(NewDateFormat))
+	Defaults.Register("date_add", fixed2(NewDateAdd))
+	Defaults.Register("date_sub", fixed2(NewDateSub))
+	Defaults.Register("datediff", fixed2(NewDateDiff))
+	Defaults.Register("now", fixed0(NewNow))
+	Defaults.Register("curdate", fixed0(NewCurDate))
+}
+
+// fixed1 adapts a single-argument constructor to sql.Function, checking
+// arity the same way the variadic constructors (e.g. NewSubstring) check
+// it themselves.
+func fixed1(fn func(sql.Expression) sql.Expression) sql.Function {
+	return func(args ...sql.Expression) (sql.Expression, error) {
+		if len(args) != 1 {
+			return nil, sql.ErrInvalidArgumentNumber.New("1", len(args))
+		}
+		return fn(args[0]), nil
+	}
+}
+
+// fixed2 adapts a two-argument constructor to sql.Function.
+func fixed2(fn func(sql.Expression, sql.Expression) sql.Expression) sql.Function {
+	return func(args ...sql.Expression) (sql.Expression, error) {
+		if len(args) != 2 {
+			return nil, sql.ErrInvalidArgumentNumber.New("2", len(args))
+		}
+		return fn(args[0], args[1]), nil
+	}
+}
+
+// fixed3 adapts a three-argument constructor to sql.Function.
+func fixed3(fn func(sql.Expression, sql.Expression, sql.Expression) sql.Expression) sql.Function {
+	return func(args ...sql.Expression) (sql.Expression, error) {
+		if len(args) != 3 {
+			return nil, sql.ErrInvalidArgumentNumber.New("3", len(args))
+		}
+		return fn(args[0], args[1], args[2]), nil
+	}
+}
+
+// fixed0 adapts a zero-argument constructor (e.g. NOW, CURDATE) to
+// sql.Function.
+func fixed0(fn func() sql.Expression) sql.Function {
+	return func(args ...sql.Expression) (sql.Expression, error) {
+		if len(args) != 0 {
+			return nil, sql.ErrInvalidArgumentNumber.New("0", len(args))
+		}
+		return fn(), nil
+	}
+}
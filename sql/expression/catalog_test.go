@@ -0,0 +1,86 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestDefaultsResolvesStringFunctions(t *testing.T) {
+	require := require.New(t)
+
+	names := []string{
+		"substring", "substring_index", "left", "right", "lpad", "rpad",
+		"replace", "locate", "instr", "trim", "ltrim", "rtrim", "reverse",
+		"repeat", "concat_ws", "char_length",
+	}
+
+	for _, name := range names {
+		_, ok := Defaults.Function(name)
+		require.True(ok, "expected %q to be registered", name)
+	}
+}
+
+func TestDefaultsResolvesTemporalFunctions(t *testing.T) {
+	require := require.New(t)
+
+	names := []string{
+		"year", "month", "day", "dayofweek", "dayofyear", "hour", "minute",
+		"second", "weekday", "date_format", "date_add", "date_sub",
+		"datediff", "now", "curdate",
+	}
+
+	for _, name := range names {
+		_, ok := Defaults.Function(name)
+		require.True(ok, "expected %q to be registered", name)
+	}
+}
+
+func TestDefaultsNowWrongArity(t *testing.T) {
+	require := require.New(t)
+
+	fn, ok := Defaults.Function("now")
+	require.True(ok)
+
+	_, err := fn(NewLiteral("a", sql.Text))
+	require.Error(err)
+}
+
+func TestDefaultsLeftWrongArity(t *testing.T) {
+	require := require.New(t)
+
+	fn, ok := Defaults.Function("left")
+	require.True(ok)
+
+	_, err := fn(NewLiteral("a", sql.Text))
+	require.Error(err)
+}
+
+func TestDefaultsIsBinaryRoundTripsModeArgument(t *testing.T) {
+	require := require.New(t)
+
+	fn, ok := Defaults.Function("is_binary")
+	require.True(ok)
+
+	expr, err := fn(NewLiteral(string([]byte{0xff, 0xfe, 'h', 0, 'i', 0}), sql.Blob), NewLiteral("utf8", sql.Text))
+	require.NoError(err)
+
+	result, err := expr.Eval(nil)
+	require.NoError(err)
+	require.Equal(true, result)
+}
+
+func TestDefaultsLeftEval(t *testing.T) {
+	require := require.New(t)
+
+	fn, ok := Defaults.Function("left")
+	require.True(ok)
+
+	expr, err := fn(NewLiteral("hello", sql.Text), NewLiteral(int64(2), sql.Int64))
+	require.NoError(err)
+
+	result, err := expr.Eval(nil)
+	require.NoError(err)
+	require.Equal("he", result)
+}
@@ -4,18 +4,49 @@ import (
 	"bytes"
 	"reflect"
 	"time"
+	"unicode/utf8"
 
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 )
 
+// BinaryMode selects the heuristic IsBinary uses to classify its input.
+type BinaryMode string
+
+const (
+	// BinaryModeGit is the default mode: a blob is binary if a NUL byte
+	// appears in its first sniffLen bytes, mirroring git's own heuristic.
+	BinaryModeGit BinaryMode = "git"
+	// BinaryModeUTF8 considers a blob binary when its sniffed bytes are not
+	// valid UTF-8. NUL bytes are valid UTF-8, so they alone do not make a
+	// blob binary in this mode.
+	BinaryModeUTF8 BinaryMode = "utf8"
+	// BinaryModeStrict considers a blob binary when it contains a NUL byte
+	// or when more than 30% of its sniffed bytes fall outside printable
+	// ASCII / common control characters, similar to file(1).
+	BinaryModeStrict BinaryMode = "strict"
+)
+
 // IsBinary is a function that returns whether a blob is binary or not.
 type IsBinary struct {
 	UnaryExpression
+	// Mode is the optional second argument selecting the classification
+	// heuristic. It is nil when IsBinary was called with a single
+	// argument, in which case BinaryModeGit is used.
+	Mode sql.Expression
 }
 
-// NewIsBinary creates a new IsBinary expression.
-func NewIsBinary(e sql.Expression) sql.Expression {
-	return &IsBinary{UnaryExpression{Child: e}}
+// NewIsBinary creates a new IsBinary expression. It accepts either a single
+// argument (the blob to classify, using BinaryModeGit) or two arguments,
+// the second being one of 'git', 'utf8' or 'strict'.
+func NewIsBinary(args ...sql.Expression) (sql.Expression, error) {
+	switch len(args) {
+	case 1:
+		return &IsBinary{UnaryExpression{Child: args[0]}, nil}, nil
+	case 2:
+		return &IsBinary{UnaryExpression{Child: args[0]}, args[1]}, nil
+	default:
+		return nil, sql.ErrInvalidArgumentNumber.New("1 or 2", len(args))
+	}
 }
 
 // Eval implements the Expression interface.
@@ -34,7 +65,24 @@ func (ib *IsBinary) Eval(row sql.Row) (interface{}, error) {
 		return nil, err
 	}
 
-	return isBinary(blob.([]byte)), nil
+	mode := BinaryModeGit
+	if ib.Mode != nil {
+		m, err := ib.Mode.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if m != nil {
+			m, err = sql.Text.Convert(m)
+			if err != nil {
+				return nil, err
+			}
+
+			mode = BinaryMode(m.(string))
+		}
+	}
+
+	return isBinary(blob.([]byte), mode), nil
 }
 
 // Name implements the Expression interface.
@@ -44,7 +92,16 @@ func (ib *IsBinary) Name() string {
 
 // TransformUp implements the Expression interface.
 func (ib *IsBinary) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
-	return NewIsBinary(ib.Child.TransformUp(f))
+	child := ib.Child.TransformUp(f)
+
+	var ib2 sql.Expression
+	if ib.Mode != nil {
+		ib2, _ = NewIsBinary(child, ib.Mode.TransformUp(f))
+	} else {
+		ib2, _ = NewIsBinary(child)
+	}
+
+	return f(ib2)
 }
 
 // Type implements the Expression interface.
@@ -54,18 +111,84 @@ func (ib *IsBinary) Type() sql.Type {
 
 const sniffLen = 8000
 
-// isBinary detects if data is a binary value based on:
-// http://git.kernel.org/cgit/git/git.git/tree/xdiff-interface.c?id=HEAD#n198
-func isBinary(data []byte) bool {
-	if len(data) > sniffLen {
+// isBinary detects if data is binary according to mode.
+func isBinary(data []byte, mode BinaryMode) bool {
+	truncated := len(data) > sniffLen
+	if truncated {
 		data = data[:sniffLen]
 	}
 
-	if bytes.IndexByte(data, byte(0)) == -1 {
+	switch mode {
+	case BinaryModeUTF8:
+		if truncated {
+			// Cutting at a fixed byte offset can land mid-rune. Back off to
+			// the last complete rune so a valid multi-byte sequence split
+			// by the sniff window isn't mistaken for invalid UTF-8.
+			data = trimIncompleteTrailingRune(data)
+		}
+		return !utf8.Valid(data)
+	case BinaryModeStrict:
+		return isBinaryStrict(data)
+	default:
+		return bytes.IndexByte(data, byte(0)) != -1
+	}
+}
+
+// trimIncompleteTrailingRune drops a final incomplete UTF-8 sequence left
+// dangling at the end of data by a byte-offset truncation. Only the last
+// few bytes are ever affected, since utf8.UTFMax bounds how long a single
+// rune's encoding can be.
+func trimIncompleteTrailingRune(data []byte) []byte {
+	end := len(data)
+
+	start := end - 1
+	for start >= 0 && start > end-utf8.UTFMax && !utf8.RuneStart(data[start]) {
+		start--
+	}
+
+	if start < 0 {
+		return data
+	}
+
+	if !utf8.FullRune(data[start:]) {
+		// The last rune's encoding is cut short by the sniff window;
+		// drop it rather than letting it look like invalid UTF-8.
+		return data[:start]
+	}
+
+	return data
+}
+
+// isBinaryStrict implements BinaryModeStrict: NUL byte present, or more
+// than 30% of the sniffed bytes fall outside printable ASCII and common
+// control characters (tab, newline, carriage return).
+func isBinaryStrict(data []byte) bool {
+	if bytes.IndexByte(data, byte(0)) != -1 {
+		return true
+	}
+
+	if len(data) == 0 {
 		return false
 	}
 
-	return true
+	var nonText int
+	for _, b := range data {
+		if isPrintableOrCommonControl(b) {
+			continue
+		}
+		nonText++
+	}
+
+	return float64(nonText)/float64(len(data)) > 0.3
+}
+
+func isPrintableOrCommonControl(b byte) bool {
+	switch b {
+	case '\t', '\n', '\r':
+		return true
+	}
+
+	return b >= 0x20 && b < 0x7f
 }
 
 // Substring is a function to return a part of a string.
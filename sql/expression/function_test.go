@@ -0,0 +1,104 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestIsBinaryGitMode(t *testing.T) {
+	require := require.New(t)
+
+	f, err := NewIsBinary(NewLiteral("hello world", sql.Blob))
+	require.NoError(err)
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(false, result)
+
+	f, err = NewIsBinary(NewLiteral("hello\x00world", sql.Blob))
+	require.NoError(err)
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal(true, result)
+}
+
+func TestIsBinaryUTF8Mode(t *testing.T) {
+	require := require.New(t)
+
+	// an invalid-UTF-8 sequence (a UTF-16LE byte order mark) is binary in
+	// 'utf8' mode...
+	f, err := NewIsBinary(NewLiteral(string([]byte{0xff, 0xfe, 'h', 0, 'i', 0}), sql.Blob), NewLiteral("utf8", sql.Text))
+	require.NoError(err)
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(true, result)
+
+	// ...but valid UTF-8 text is not, even with runes outside ASCII.
+	f, err = NewIsBinary(NewLiteral("héllo wörld", sql.Blob), NewLiteral("utf8", sql.Text))
+	require.NoError(err)
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal(false, result)
+}
+
+func TestIsBinaryStrictMode(t *testing.T) {
+	require := require.New(t)
+
+	// plain text stays non-binary.
+	f, err := NewIsBinary(NewLiteral("the quick brown fox\n", sql.Blob), NewLiteral("strict", sql.Text))
+	require.NoError(err)
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(false, result)
+
+	// a payload with no NUL byte but mostly non-printable content is
+	// still classified as binary, unlike 'git' mode.
+	nonPrintable := strings.Repeat("\x01\x02\x03\x04", 100)
+	f, err = NewIsBinary(NewLiteral(nonPrintable, sql.Blob), NewLiteral("strict", sql.Text))
+	require.NoError(err)
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal(true, result)
+}
+
+func TestIsBinaryUTF8ModeDoesNotSplitRuneAtSniffBoundary(t *testing.T) {
+	require := require.New(t)
+
+	// Place a 2-byte rune (é, 0xC3 0xA9) straddling the 8000-byte sniff
+	// window, so naive truncation would cut it in half and leave a
+	// dangling lead byte that looks like invalid UTF-8.
+	var data []byte
+	data = append(data, []byte(strings.Repeat("a", sniffLen-1))...)
+	data = append(data, []byte("é")...)
+	data = append(data, []byte(strings.Repeat("b", 100))...)
+	require.Len(data, sniffLen+101)
+
+	f, err := NewIsBinary(NewLiteral(string(data), sql.Blob), NewLiteral("utf8", sql.Text))
+	require.NoError(err)
+
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(false, result)
+}
+
+func TestIsBinaryNilIsNotBinary(t *testing.T) {
+	require := require.New(t)
+
+	f, err := NewIsBinary(NewLiteral(nil, sql.Blob))
+	require.NoError(err)
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(false, result)
+}
+
+func TestNewIsBinaryInvalidArgumentNumber(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewIsBinary()
+	require.Error(err)
+
+	_, err = NewIsBinary(NewLiteral("a", sql.Blob), NewLiteral("b", sql.Text), NewLiteral("c", sql.Text))
+	require.Error(err)
+}
@@ -0,0 +1,778 @@
+package expression
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// toRunes evaluates e over row and converts the result to a []rune, like
+// Substring does for its first argument, but coercing non-string values
+// through sql.Text.Convert. It returns null=true when the value is nil, in
+// which case callers must propagate nil.
+func toRunes(e sql.Expression, row sql.Row) (text []rune, null bool, err error) {
+	v, err := e.Eval(row)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if v == nil {
+		return nil, true, nil
+	}
+
+	v, err = sql.Text.Convert(v)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return []rune(v.(string)), false, nil
+}
+
+// SubstringIndex is a function that returns the substring of a string
+// before a given number of occurrences of a delimiter.
+type SubstringIndex struct {
+	str   sql.Expression
+	delim sql.Expression
+	count sql.Expression
+}
+
+// NewSubstringIndex creates a new SubstringIndex UDF.
+func NewSubstringIndex(str, delim, count sql.Expression) sql.Expression {
+	return &SubstringIndex{str, delim, count}
+}
+
+// Name implements the Expression interface.
+func (s *SubstringIndex) Name() string { return "substring_index" }
+
+// Type implements the Expression interface.
+func (s *SubstringIndex) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (s *SubstringIndex) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (s *SubstringIndex) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (s *SubstringIndex) Eval(row sql.Row) (interface{}, error) {
+	str, null, err := toRunes(s.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	delim, null, err := toRunes(s.delim, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	c, err := s.count.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, nil
+	}
+
+	c, err = sql.Int64.Convert(c)
+	if err != nil {
+		return nil, err
+	}
+	count := c.(int64)
+
+	if count == 0 || len(delim) == 0 {
+		return "", nil
+	}
+
+	parts := strings.Split(string(str), string(delim))
+
+	if count > 0 {
+		if int(count) >= len(parts) {
+			return string(str), nil
+		}
+		return strings.Join(parts[:count], string(delim)), nil
+	}
+
+	count = -count
+	if int(count) >= len(parts) {
+		return string(str), nil
+	}
+	return strings.Join(parts[len(parts)-int(count):], string(delim)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (s *SubstringIndex) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewSubstringIndex(
+		s.str.TransformUp(f),
+		s.delim.TransformUp(f),
+		s.count.TransformUp(f),
+	))
+}
+
+// Left is a function that returns the first n characters of a string.
+type Left struct {
+	str sql.Expression
+	len sql.Expression
+}
+
+// NewLeft creates a new Left UDF.
+func NewLeft(str, length sql.Expression) sql.Expression {
+	return &Left{str, length}
+}
+
+// Name implements the Expression interface.
+func (l *Left) Name() string { return "left" }
+
+// Type implements the Expression interface.
+func (l *Left) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (l *Left) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (l *Left) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (l *Left) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(l.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := l.len.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	length := v.(int64)
+
+	if length <= 0 {
+		return "", nil
+	}
+	if length > int64(len(text)) {
+		length = int64(len(text))
+	}
+
+	return string(text[:length]), nil
+}
+
+// TransformUp implements the Expression interface.
+func (l *Left) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewLeft(l.str.TransformUp(f), l.len.TransformUp(f)))
+}
+
+// Right is a function that returns the last n characters of a string.
+type Right struct {
+	str sql.Expression
+	len sql.Expression
+}
+
+// NewRight creates a new Right UDF.
+func NewRight(str, length sql.Expression) sql.Expression {
+	return &Right{str, length}
+}
+
+// Name implements the Expression interface.
+func (r *Right) Name() string { return "right" }
+
+// Type implements the Expression interface.
+func (r *Right) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (r *Right) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (r *Right) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (r *Right) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(r.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := r.len.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	length := v.(int64)
+
+	if length <= 0 {
+		return "", nil
+	}
+	if length > int64(len(text)) {
+		length = int64(len(text))
+	}
+
+	return string(text[int64(len(text))-length:]), nil
+}
+
+// TransformUp implements the Expression interface.
+func (r *Right) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewRight(r.str.TransformUp(f), r.len.TransformUp(f)))
+}
+
+// pad implements the shared logic between LPad and RPad.
+func pad(str, padStr []rune, length int64, left bool) string {
+	if length <= 0 {
+		return ""
+	}
+
+	if int64(len(str)) >= length {
+		return string(str[:length])
+	}
+
+	if len(padStr) == 0 {
+		return string(str)
+	}
+
+	missing := length - int64(len(str))
+	fill := make([]rune, missing)
+	for i := range fill {
+		fill[i] = padStr[i%len(padStr)]
+	}
+
+	if left {
+		return string(fill) + string(str)
+	}
+	return string(str) + string(fill)
+}
+
+// LPad is a function that left-pads a string with another string up to a
+// given length.
+type LPad struct {
+	str    sql.Expression
+	len    sql.Expression
+	padStr sql.Expression
+}
+
+// NewLPad creates a new LPad UDF.
+func NewLPad(str, length, padStr sql.Expression) sql.Expression {
+	return &LPad{str, length, padStr}
+}
+
+// Name implements the Expression interface.
+func (p *LPad) Name() string { return "lpad" }
+
+// Type implements the Expression interface.
+func (p *LPad) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (p *LPad) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (p *LPad) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (p *LPad) Eval(row sql.Row) (interface{}, error) {
+	str, null, err := toRunes(p.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	padStr, null, err := toRunes(p.padStr, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := p.len.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return pad(str, padStr, v.(int64), true), nil
+}
+
+// TransformUp implements the Expression interface.
+func (p *LPad) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewLPad(p.str.TransformUp(f), p.len.TransformUp(f), p.padStr.TransformUp(f)))
+}
+
+// RPad is a function that right-pads a string with another string up to a
+// given length.
+type RPad struct {
+	str    sql.Expression
+	len    sql.Expression
+	padStr sql.Expression
+}
+
+// NewRPad creates a new RPad UDF.
+func NewRPad(str, length, padStr sql.Expression) sql.Expression {
+	return &RPad{str, length, padStr}
+}
+
+// Name implements the Expression interface.
+func (p *RPad) Name() string { return "rpad" }
+
+// Type implements the Expression interface.
+func (p *RPad) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (p *RPad) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (p *RPad) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (p *RPad) Eval(row sql.Row) (interface{}, error) {
+	str, null, err := toRunes(p.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	padStr, null, err := toRunes(p.padStr, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := p.len.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return pad(str, padStr, v.(int64), false), nil
+}
+
+// TransformUp implements the Expression interface.
+func (p *RPad) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewRPad(p.str.TransformUp(f), p.len.TransformUp(f), p.padStr.TransformUp(f)))
+}
+
+// Replace is a function that replaces all occurrences of a substring
+// within a string with another substring.
+type Replace struct {
+	str  sql.Expression
+	from sql.Expression
+	to   sql.Expression
+}
+
+// NewReplace creates a new Replace UDF.
+func NewReplace(str, from, to sql.Expression) sql.Expression {
+	return &Replace{str, from, to}
+}
+
+// Name implements the Expression interface.
+func (r *Replace) Name() string { return "replace" }
+
+// Type implements the Expression interface.
+func (r *Replace) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (r *Replace) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (r *Replace) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (r *Replace) Eval(row sql.Row) (interface{}, error) {
+	str, null, err := toRunes(r.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	from, null, err := toRunes(r.from, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	to, null, err := toRunes(r.to, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	if len(from) == 0 {
+		return string(str), nil
+	}
+
+	return strings.Replace(string(str), string(from), string(to), -1), nil
+}
+
+// TransformUp implements the Expression interface.
+func (r *Replace) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewReplace(r.str.TransformUp(f), r.from.TransformUp(f), r.to.TransformUp(f)))
+}
+
+// Locate is a function that returns the 1-based rune position of the first
+// occurrence of a substring within a string, optionally starting the
+// search at a given 1-based position. It implements both LOCATE and
+// INSTR, which only differ in argument order.
+type Locate struct {
+	substr sql.Expression
+	str    sql.Expression
+	start  sql.Expression
+}
+
+// NewLocate creates a new Locate UDF.
+func NewLocate(args ...sql.Expression) (sql.Expression, error) {
+	var substr, str, start sql.Expression
+	switch len(args) {
+	case 2:
+		substr = args[0]
+		str = args[1]
+	case 3:
+		substr = args[0]
+		str = args[1]
+		start = args[2]
+	default:
+		return nil, sql.ErrInvalidArgumentNumber.New("2 or 3", len(args))
+	}
+	return &Locate{substr, str, start}, nil
+}
+
+// NewInstr creates a new INSTR UDF, which is LOCATE with its first two
+// arguments swapped.
+func NewInstr(str, substr sql.Expression) sql.Expression {
+	l, _ := NewLocate(substr, str)
+	return l
+}
+
+// Name implements the Expression interface.
+func (l *Locate) Name() string { return "locate" }
+
+// Type implements the Expression interface.
+func (l *Locate) Type() sql.Type { return sql.Int64 }
+
+// IsNullable implements the Expression interface.
+func (l *Locate) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (l *Locate) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (l *Locate) Eval(row sql.Row) (interface{}, error) {
+	substr, null, err := toRunes(l.substr, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	str, null, err := toRunes(l.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	var start int64 = 1
+	if l.start != nil {
+		v, err := l.start.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+
+		v, err = sql.Int64.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		start = v.(int64)
+	}
+
+	if start < 1 {
+		start = 1
+	}
+	if start > int64(len(str))+1 {
+		return int64(0), nil
+	}
+
+	idx := strings.Index(string(str[start-1:]), string(substr))
+	if idx == -1 {
+		return int64(0), nil
+	}
+
+	return start + int64(len([]rune(string(str[start-1:])[:idx]))), nil
+}
+
+// TransformUp implements the Expression interface.
+func (l *Locate) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	args := []sql.Expression{l.substr.TransformUp(f), l.str.TransformUp(f)}
+	if l.start != nil {
+		args = append(args, l.start.TransformUp(f))
+	}
+
+	loc, _ := NewLocate(args...)
+	return f(loc)
+}
+
+// trimFunc abstracts the direction-specific behaviour shared by Trim,
+// LTrim and RTrim.
+type trimFunc struct {
+	UnaryExpression
+	cutset string
+	mode   trimMode
+}
+
+type trimMode byte
+
+const (
+	trimBoth trimMode = iota
+	trimLeft
+	trimRight
+)
+
+// NewTrim creates a new TRIM UDF.
+func NewTrim(str sql.Expression) sql.Expression {
+	return &trimFunc{UnaryExpression{Child: str}, " \t\n\r", trimBoth}
+}
+
+// NewLTrim creates a new LTRIM UDF.
+func NewLTrim(str sql.Expression) sql.Expression {
+	return &trimFunc{UnaryExpression{Child: str}, " \t\n\r", trimLeft}
+}
+
+// NewRTrim creates a new RTRIM UDF.
+func NewRTrim(str sql.Expression) sql.Expression {
+	return &trimFunc{UnaryExpression{Child: str}, " \t\n\r", trimRight}
+}
+
+// Name implements the Expression interface.
+func (t *trimFunc) Name() string {
+	switch t.mode {
+	case trimLeft:
+		return "ltrim"
+	case trimRight:
+		return "rtrim"
+	default:
+		return "trim"
+	}
+}
+
+// Type implements the Expression interface.
+func (t *trimFunc) Type() sql.Type { return sql.Text }
+
+// Eval implements the Expression interface.
+func (t *trimFunc) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(t.Child, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	switch t.mode {
+	case trimLeft:
+		return strings.TrimLeft(string(text), t.cutset), nil
+	case trimRight:
+		return strings.TrimRight(string(text), t.cutset), nil
+	default:
+		return strings.Trim(string(text), t.cutset), nil
+	}
+}
+
+// TransformUp implements the Expression interface.
+func (t *trimFunc) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(&trimFunc{UnaryExpression{Child: t.Child.TransformUp(f)}, t.cutset, t.mode})
+}
+
+// Reverse is a function that reverses a string, rune by rune.
+type Reverse struct {
+	UnaryExpression
+}
+
+// NewReverse creates a new Reverse UDF.
+func NewReverse(str sql.Expression) sql.Expression {
+	return &Reverse{UnaryExpression{Child: str}}
+}
+
+// Name implements the Expression interface.
+func (r *Reverse) Name() string { return "reverse" }
+
+// Type implements the Expression interface.
+func (r *Reverse) Type() sql.Type { return sql.Text }
+
+// Eval implements the Expression interface.
+func (r *Reverse) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(r.Child, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	reversed := make([]rune, len(text))
+	for i, c := range text {
+		reversed[len(text)-1-i] = c
+	}
+
+	return string(reversed), nil
+}
+
+// TransformUp implements the Expression interface.
+func (r *Reverse) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewReverse(r.Child.TransformUp(f)))
+}
+
+// Repeat is a function that repeats a string a given number of times.
+type Repeat struct {
+	str   sql.Expression
+	count sql.Expression
+}
+
+// NewRepeat creates a new Repeat UDF.
+func NewRepeat(str, count sql.Expression) sql.Expression {
+	return &Repeat{str, count}
+}
+
+// Name implements the Expression interface.
+func (r *Repeat) Name() string { return "repeat" }
+
+// Type implements the Expression interface.
+func (r *Repeat) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (r *Repeat) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (r *Repeat) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (r *Repeat) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(r.str, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := r.count.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	count := v.(int64)
+
+	if count <= 0 {
+		return "", nil
+	}
+
+	return strings.Repeat(string(text), int(count)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (r *Repeat) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewRepeat(r.str.TransformUp(f), r.count.TransformUp(f)))
+}
+
+// ConcatWithSeparator is a function that concatenates its arguments using
+// the first argument as separator, implementing MySQL's CONCAT_WS.
+type ConcatWithSeparator struct {
+	sep  sql.Expression
+	args []sql.Expression
+}
+
+// NewConcatWithSeparator creates a new CONCAT_WS UDF.
+func NewConcatWithSeparator(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("2 or more", len(args))
+	}
+	return &ConcatWithSeparator{args[0], args[1:]}, nil
+}
+
+// Name implements the Expression interface.
+func (c *ConcatWithSeparator) Name() string { return "concat_ws" }
+
+// Type implements the Expression interface.
+func (c *ConcatWithSeparator) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (c *ConcatWithSeparator) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (c *ConcatWithSeparator) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (c *ConcatWithSeparator) Eval(row sql.Row) (interface{}, error) {
+	sep, null, err := toRunes(c.sep, row)
+	if err != nil {
+		return nil, err
+	}
+	if null {
+		return nil, nil
+	}
+
+	var parts []string
+	for _, a := range c.args {
+		text, null, err := toRunes(a, row)
+		if err != nil {
+			return nil, err
+		}
+		if null {
+			// NULL arguments are simply skipped by CONCAT_WS, unlike CONCAT.
+			continue
+		}
+		parts = append(parts, string(text))
+	}
+
+	return strings.Join(parts, string(sep)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (c *ConcatWithSeparator) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	args := make([]sql.Expression, len(c.args))
+	for i, a := range c.args {
+		args[i] = a.TransformUp(f)
+	}
+	concat, _ := NewConcatWithSeparator(append([]sql.Expression{c.sep.TransformUp(f)}, args...)...)
+	return f(concat)
+}
+
+// CharLength is a function that returns the number of runes in a string.
+type CharLength struct {
+	UnaryExpression
+}
+
+// NewCharLength creates a new CharLength UDF.
+func NewCharLength(str sql.Expression) sql.Expression {
+	return &CharLength{UnaryExpression{Child: str}}
+}
+
+// Name implements the Expression interface.
+func (c *CharLength) Name() string { return "char_length" }
+
+// Type implements the Expression interface.
+func (c *CharLength) Type() sql.Type { return sql.Int64 }
+
+// Eval implements the Expression interface.
+func (c *CharLength) Eval(row sql.Row) (interface{}, error) {
+	text, null, err := toRunes(c.Child, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	return int64(len(text)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (c *CharLength) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewCharLength(c.Child.TransformUp(f)))
+}
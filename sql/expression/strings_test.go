@@ -0,0 +1,197 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestSubstringIndex(t *testing.T) {
+	testCases := []struct {
+		str, delim string
+		count      int64
+		expected   string
+	}{
+		{"a.b.c", ".", 2, "a.b"},
+		{"a.b.c", ".", -2, "b.c"},
+		{"a.b.c", ".", 0, ""},
+		{"a.b.c", ".", 10, "a.b.c"},
+		{"a.b.c", ".", -10, "a.b.c"},
+	}
+
+	for _, tc := range testCases {
+		f := NewSubstringIndex(
+			NewLiteral(tc.str, sql.Text),
+			NewLiteral(tc.delim, sql.Text),
+			NewLiteral(tc.count, sql.Int64),
+		)
+
+		result, err := f.Eval(nil)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, result)
+	}
+}
+
+func TestLeftRight(t *testing.T) {
+	require := require.New(t)
+
+	left := NewLeft(NewLiteral("á1é", sql.Text), NewLiteral(int64(1), sql.Int64))
+	result, err := left.Eval(nil)
+	require.NoError(err)
+	require.Equal("á", result)
+
+	right := NewRight(NewLiteral("á1é", sql.Text), NewLiteral(int64(1), sql.Int64))
+	result, err = right.Eval(nil)
+	require.NoError(err)
+	require.Equal("é", result)
+
+	// negative/zero length returns empty string, not an error
+	left = NewLeft(NewLiteral("abc", sql.Text), NewLiteral(int64(0), sql.Int64))
+	result, err = left.Eval(nil)
+	require.NoError(err)
+	require.Equal("", result)
+}
+
+func TestLeftRightNilPropagation(t *testing.T) {
+	require := require.New(t)
+
+	left := NewLeft(NewLiteral(nil, sql.Text), NewLiteral(int64(1), sql.Int64))
+	result, err := left.Eval(nil)
+	require.NoError(err)
+	require.Nil(result)
+
+	right := NewRight(NewLiteral("abc", sql.Text), NewLiteral(nil, sql.Int64))
+	result, err = right.Eval(nil)
+	require.NoError(err)
+	require.Nil(result)
+}
+
+func TestLPadRPad(t *testing.T) {
+	require := require.New(t)
+
+	lpad := NewLPad(NewLiteral("1", sql.Text), NewLiteral(int64(3), sql.Int64), NewLiteral("0", sql.Text))
+	result, err := lpad.Eval(nil)
+	require.NoError(err)
+	require.Equal("001", result)
+
+	rpad := NewRPad(NewLiteral("1", sql.Text), NewLiteral(int64(3), sql.Int64), NewLiteral("0", sql.Text))
+	result, err = rpad.Eval(nil)
+	require.NoError(err)
+	require.Equal("100", result)
+
+	// length shorter than the string truncates instead of padding
+	lpad = NewLPad(NewLiteral("hello", sql.Text), NewLiteral(int64(2), sql.Int64), NewLiteral("0", sql.Text))
+	result, err = lpad.Eval(nil)
+	require.NoError(err)
+	require.Equal("he", result)
+}
+
+func TestReplace(t *testing.T) {
+	require := require.New(t)
+
+	f := NewReplace(
+		NewLiteral("foo bar foo", sql.Text),
+		NewLiteral("foo", sql.Text),
+		NewLiteral("baz", sql.Text),
+	)
+
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal("baz bar baz", result)
+}
+
+func TestLocate(t *testing.T) {
+	require := require.New(t)
+
+	f, err := NewLocate(NewLiteral("bar", sql.Text), NewLiteral("foobarbar", sql.Text))
+	require.NoError(err)
+
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal(int64(4), result)
+
+	f, err = NewLocate(NewLiteral("bar", sql.Text), NewLiteral("foobarbar", sql.Text), NewLiteral(int64(5), sql.Int64))
+	require.NoError(err)
+
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal(int64(7), result)
+
+	f, err = NewLocate(NewLiteral("xxx", sql.Text), NewLiteral("foobarbar", sql.Text))
+	require.NoError(err)
+
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal(int64(0), result)
+}
+
+func TestTrimFamily(t *testing.T) {
+	require := require.New(t)
+
+	result, err := NewTrim(NewLiteral("  hi  ", sql.Text)).Eval(nil)
+	require.NoError(err)
+	require.Equal("hi", result)
+
+	result, err = NewLTrim(NewLiteral("  hi  ", sql.Text)).Eval(nil)
+	require.NoError(err)
+	require.Equal("hi  ", result)
+
+	result, err = NewRTrim(NewLiteral("  hi  ", sql.Text)).Eval(nil)
+	require.NoError(err)
+	require.Equal("  hi", result)
+}
+
+func TestReverse(t *testing.T) {
+	require := require.New(t)
+
+	result, err := NewReverse(NewLiteral("áéí", sql.Text)).Eval(nil)
+	require.NoError(err)
+	require.Equal("íéá", result)
+}
+
+func TestRepeat(t *testing.T) {
+	require := require.New(t)
+
+	result, err := NewRepeat(NewLiteral("ab", sql.Text), NewLiteral(int64(3), sql.Int64)).Eval(nil)
+	require.NoError(err)
+	require.Equal("ababab", result)
+
+	result, err = NewRepeat(NewLiteral("ab", sql.Text), NewLiteral(int64(0), sql.Int64)).Eval(nil)
+	require.NoError(err)
+	require.Equal("", result)
+}
+
+func TestConcatWithSeparator(t *testing.T) {
+	require := require.New(t)
+
+	f, err := NewConcatWithSeparator(
+		NewLiteral(",", sql.Text),
+		NewLiteral("a", sql.Text),
+		NewLiteral(nil, sql.Text),
+		NewLiteral("b", sql.Text),
+	)
+	require.NoError(err)
+
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal("a,b", result)
+}
+
+func TestCharLength(t *testing.T) {
+	require := require.New(t)
+
+	result, err := NewCharLength(NewLiteral("áéí", sql.Text)).Eval(nil)
+	require.NoError(err)
+	require.Equal(int64(3), result)
+}
+
+func TestStringFunctionsCoerceNonStringArgs(t *testing.T) {
+	require := require.New(t)
+
+	// an integer argument must be coerced through sql.Text.Convert the same
+	// way a literal string would be.
+	result, err := NewReverse(NewLiteral(int64(123), sql.Int64)).Eval(nil)
+	require.NoError(err)
+	require.Equal("321", result)
+}
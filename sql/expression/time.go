@@ -0,0 +1,365 @@
+package expression
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// toDate evaluates e over row and converts the result through sql.Date,
+// the same coercion Year.Eval already uses, so strings, time.Time and
+// integer epoch values are all accepted. It returns null=true when the
+// value is nil, in which case callers must propagate nil.
+func toDate(e sql.Expression, row sql.Row) (t time.Time, null bool, err error) {
+	v, err := e.Eval(row)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if v == nil {
+		return time.Time{}, true, nil
+	}
+
+	v, err = sql.Date.Convert(v)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return v.(time.Time), false, nil
+}
+
+// datePartFunc is the shared shape of the single-argument date-part
+// extractors (Month, Day, Hour, ...), which only differ in name and in how
+// they pull their part out of a time.Time.
+type datePartFunc struct {
+	UnaryExpression
+	name string
+	part func(time.Time) int32
+}
+
+func newDatePartFunc(name string, part func(time.Time) int32) func(sql.Expression) sql.Expression {
+	return func(date sql.Expression) sql.Expression {
+		return &datePartFunc{UnaryExpression{Child: date}, name, part}
+	}
+}
+
+// NewMonth creates a new MONTH UDF.
+var NewMonth = newDatePartFunc("month", func(t time.Time) int32 { return int32(t.Month()) })
+
+// NewDay creates a new DAY UDF.
+var NewDay = newDatePartFunc("day", func(t time.Time) int32 { return int32(t.Day()) })
+
+// NewDayOfWeek creates a new DAYOFWEEK UDF. It follows MySQL's convention
+// of Sunday = 1 ... Saturday = 7.
+var NewDayOfWeek = newDatePartFunc("dayofweek", func(t time.Time) int32 { return int32(t.Weekday()) + 1 })
+
+// NewDayOfYear creates a new DAYOFYEAR UDF.
+var NewDayOfYear = newDatePartFunc("dayofyear", func(t time.Time) int32 { return int32(t.YearDay()) })
+
+// NewHour creates a new HOUR UDF.
+var NewHour = newDatePartFunc("hour", func(t time.Time) int32 { return int32(t.Hour()) })
+
+// NewMinute creates a new MINUTE UDF.
+var NewMinute = newDatePartFunc("minute", func(t time.Time) int32 { return int32(t.Minute()) })
+
+// NewSecond creates a new SECOND UDF.
+var NewSecond = newDatePartFunc("second", func(t time.Time) int32 { return int32(t.Second()) })
+
+// NewWeekday creates a new WEEKDAY UDF. It follows MySQL's convention of
+// Monday = 0 ... Sunday = 6, unlike DAYOFWEEK.
+var NewWeekday = newDatePartFunc("weekday", func(t time.Time) int32 {
+	return int32((t.Weekday() + 6) % 7)
+})
+
+// Name implements the Expression interface.
+func (f *datePartFunc) Name() string { return f.name }
+
+// Type implements the Expression interface.
+func (f *datePartFunc) Type() sql.Type { return sql.Int32 }
+
+// Eval implements the Expression interface.
+func (f *datePartFunc) Eval(row sql.Row) (interface{}, error) {
+	date, null, err := toDate(f.Child, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	return f.part(date), nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *datePartFunc) TransformUp(fn func(sql.Expression) sql.Expression) sql.Expression {
+	return fn(&datePartFunc{UnaryExpression{Child: f.Child.TransformUp(fn)}, f.name, f.part})
+}
+
+// dateFormatSpecifiers maps MySQL's DATE_FORMAT specifiers to a function
+// that renders that piece of a time.Time. Only the subset commonly seen in
+// the wild is implemented; unknown specifiers are passed through as-is.
+var dateFormatSpecifiers = map[byte]func(time.Time) string{
+	'Y': func(t time.Time) string { return strconv.Itoa(t.Year()) },
+	'y': func(t time.Time) string { return t.Format("06") },
+	'm': func(t time.Time) string { return t.Format("01") },
+	'c': func(t time.Time) string { return strconv.Itoa(int(t.Month())) },
+	'd': func(t time.Time) string { return t.Format("02") },
+	'e': func(t time.Time) string { return strconv.Itoa(t.Day()) },
+	'H': func(t time.Time) string { return t.Format("15") },
+	'h': func(t time.Time) string { return t.Format("03") },
+	'i': func(t time.Time) string { return t.Format("04") },
+	's': func(t time.Time) string { return t.Format("05") },
+	'p': func(t time.Time) string { return t.Format("PM") },
+	'W': func(t time.Time) string { return t.Weekday().String() },
+	'a': func(t time.Time) string { return t.Weekday().String()[:3] },
+	'M': func(t time.Time) string { return t.Month().String() },
+	'b': func(t time.Time) string { return t.Month().String()[:3] },
+	'j': func(t time.Time) string { return strconv.Itoa(t.YearDay()) },
+	'%': func(t time.Time) string { return "%" },
+}
+
+// formatDate renders t according to a MySQL DATE_FORMAT format string by
+// walking it byte by byte, emitting literal runes for anything that is not
+// a recognised %-specifier.
+func formatDate(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 >= len(format) {
+			b.WriteByte(c)
+			continue
+		}
+
+		spec := format[i+1]
+		if fn, ok := dateFormatSpecifiers[spec]; ok {
+			b.WriteString(fn(t))
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(spec)
+		}
+		i++
+	}
+	return b.String()
+}
+
+// DateFormat is a function that formats a date according to a MySQL
+// DATE_FORMAT-style format string.
+type DateFormat struct {
+	date   sql.Expression
+	format sql.Expression
+}
+
+// NewDateFormat creates a new DATE_FORMAT UDF.
+func NewDateFormat(date, format sql.Expression) sql.Expression {
+	return &DateFormat{date, format}
+}
+
+// Name implements the Expression interface.
+func (d *DateFormat) Name() string { return "date_format" }
+
+// Type implements the Expression interface.
+func (d *DateFormat) Type() sql.Type { return sql.Text }
+
+// IsNullable implements the Expression interface.
+func (d *DateFormat) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (d *DateFormat) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (d *DateFormat) Eval(row sql.Row) (interface{}, error) {
+	date, null, err := toDate(d.date, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	f, err := d.format.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	f, err = sql.Text.Convert(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatDate(date, f.(string)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (d *DateFormat) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewDateFormat(d.date.TransformUp(f), d.format.TransformUp(f)))
+}
+
+// dateOffsetFunc is the shared shape of DATE_ADD and DATE_SUB, which only
+// differ in the sign applied to the interval.
+type dateOffsetFunc struct {
+	date     sql.Expression
+	interval sql.Expression
+	name     string
+	sign     time.Duration
+}
+
+// NewDateAdd creates a new DATE_ADD UDF. interval is evaluated as a number
+// of days, the most common case; fractional intervals are not supported.
+func NewDateAdd(date, interval sql.Expression) sql.Expression {
+	return &dateOffsetFunc{date, interval, "date_add", 1}
+}
+
+// NewDateSub creates a new DATE_SUB UDF.
+func NewDateSub(date, interval sql.Expression) sql.Expression {
+	return &dateOffsetFunc{date, interval, "date_sub", -1}
+}
+
+// Name implements the Expression interface.
+func (d *dateOffsetFunc) Name() string { return d.name }
+
+// Type implements the Expression interface.
+func (d *dateOffsetFunc) Type() sql.Type { return sql.Date }
+
+// IsNullable implements the Expression interface.
+func (d *dateOffsetFunc) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (d *dateOffsetFunc) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (d *dateOffsetFunc) Eval(row sql.Row) (interface{}, error) {
+	date, null, err := toDate(d.date, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	v, err := d.interval.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	v, err = sql.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	days := time.Duration(v.(int64)) * d.sign
+	return date.AddDate(0, 0, int(days)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (d *dateOffsetFunc) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(&dateOffsetFunc{d.date.TransformUp(f), d.interval.TransformUp(f), d.name, d.sign})
+}
+
+// DateDiff is a function that returns the number of days between two
+// dates.
+type DateDiff struct {
+	first  sql.Expression
+	second sql.Expression
+}
+
+// NewDateDiff creates a new DATEDIFF UDF.
+func NewDateDiff(first, second sql.Expression) sql.Expression {
+	return &DateDiff{first, second}
+}
+
+// Name implements the Expression interface.
+func (d *DateDiff) Name() string { return "datediff" }
+
+// Type implements the Expression interface.
+func (d *DateDiff) Type() sql.Type { return sql.Int64 }
+
+// IsNullable implements the Expression interface.
+func (d *DateDiff) IsNullable() bool { return true }
+
+// Resolved implements the Expression interface.
+func (d *DateDiff) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (d *DateDiff) Eval(row sql.Row) (interface{}, error) {
+	first, null, err := toDate(d.first, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	second, null, err := toDate(d.second, row)
+	if err != nil || null {
+		return nil, err
+	}
+
+	first = first.Truncate(24 * time.Hour)
+	second = second.Truncate(24 * time.Hour)
+
+	return int64(first.Sub(second) / (24 * time.Hour)), nil
+}
+
+// TransformUp implements the Expression interface.
+func (d *DateDiff) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(NewDateDiff(d.first.TransformUp(f), d.second.TransformUp(f)))
+}
+
+// Now is a function that returns the current date and time. It takes no
+// arguments and is never nullable.
+type Now struct{}
+
+// NewNow creates a new NOW UDF.
+func NewNow() sql.Expression {
+	return &Now{}
+}
+
+// Name implements the Expression interface.
+func (n *Now) Name() string { return "now" }
+
+// Type implements the Expression interface.
+func (n *Now) Type() sql.Type { return sql.Date }
+
+// IsNullable implements the Expression interface.
+func (n *Now) IsNullable() bool { return false }
+
+// Resolved implements the Expression interface.
+func (n *Now) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (n *Now) Eval(row sql.Row) (interface{}, error) {
+	return time.Now(), nil
+}
+
+// TransformUp implements the Expression interface.
+func (n *Now) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(n)
+}
+
+// CurDate is a function that returns the current date, with the time part
+// truncated to midnight.
+type CurDate struct{}
+
+// NewCurDate creates a new CURDATE UDF.
+func NewCurDate() sql.Expression {
+	return &CurDate{}
+}
+
+// Name implements the Expression interface.
+func (c *CurDate) Name() string { return "curdate" }
+
+// Type implements the Expression interface.
+func (c *CurDate) Type() sql.Type { return sql.Date }
+
+// IsNullable implements the Expression interface.
+func (c *CurDate) IsNullable() bool { return false }
+
+// Resolved implements the Expression interface.
+func (c *CurDate) Resolved() bool { return true }
+
+// Eval implements the Expression interface.
+func (c *CurDate) Eval(row sql.Row) (interface{}, error) {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+}
+
+// TransformUp implements the Expression interface.
+func (c *CurDate) TransformUp(f func(sql.Expression) sql.Expression) sql.Expression {
+	return f(c)
+}
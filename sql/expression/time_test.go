@@ -0,0 +1,103 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestDateParts(t *testing.T) {
+	require := require.New(t)
+
+	date := NewLiteral(time.Date(2018, time.June, 15, 10, 24, 36, 0, time.UTC), sql.Date)
+
+	result, err := NewMonth(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(6), result)
+
+	result, err = NewDay(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(15), result)
+
+	result, err = NewHour(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(10), result)
+
+	result, err = NewMinute(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(24), result)
+
+	result, err = NewSecond(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(36), result)
+
+	// 2018-06-15 is a Friday: DAYOFWEEK is 1-indexed from Sunday (6),
+	// WEEKDAY is 0-indexed from Monday (4).
+	result, err = NewDayOfWeek(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(6), result)
+
+	result, err = NewWeekday(date).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(4), result)
+}
+
+func TestDatePartsNilPropagation(t *testing.T) {
+	require := require.New(t)
+
+	result, err := NewMonth(NewLiteral(nil, sql.Date)).Eval(nil)
+	require.NoError(err)
+	require.Nil(result)
+}
+
+func TestDatePartsCoercesEpoch(t *testing.T) {
+	require := require.New(t)
+
+	// 1528972800 == 2018-06-14T10:40:00Z
+	result, err := NewDay(NewLiteral(int64(1528972800), sql.Date)).Eval(nil)
+	require.NoError(err)
+	require.Equal(int32(14), result)
+}
+
+func TestDateFormat(t *testing.T) {
+	require := require.New(t)
+
+	date := NewLiteral(time.Date(2018, time.June, 15, 10, 24, 36, 0, time.UTC), sql.Date)
+
+	f := NewDateFormat(date, NewLiteral("%Y-%m-%d %H:%i:%s", sql.Text))
+	result, err := f.Eval(nil)
+	require.NoError(err)
+	require.Equal("2018-06-15 10:24:36", result)
+
+	f = NewDateFormat(date, NewLiteral("%W, %M %e", sql.Text))
+	result, err = f.Eval(nil)
+	require.NoError(err)
+	require.Equal("Friday, June 15", result)
+}
+
+func TestDateAddSub(t *testing.T) {
+	require := require.New(t)
+
+	date := NewLiteral(time.Date(2018, time.June, 15, 0, 0, 0, 0, time.UTC), sql.Date)
+
+	result, err := NewDateAdd(date, NewLiteral(int64(5), sql.Int64)).Eval(nil)
+	require.NoError(err)
+	require.Equal(time.Date(2018, time.June, 20, 0, 0, 0, 0, time.UTC), result)
+
+	result, err = NewDateSub(date, NewLiteral(int64(5), sql.Int64)).Eval(nil)
+	require.NoError(err)
+	require.Equal(time.Date(2018, time.June, 10, 0, 0, 0, 0, time.UTC), result)
+}
+
+func TestDateDiff(t *testing.T) {
+	require := require.New(t)
+
+	first := NewLiteral(time.Date(2018, time.June, 20, 0, 0, 0, 0, time.UTC), sql.Date)
+	second := NewLiteral(time.Date(2018, time.June, 15, 0, 0, 0, 0, time.UTC), sql.Date)
+
+	result, err := NewDateDiff(first, second).Eval(nil)
+	require.NoError(err)
+	require.Equal(int64(5), result)
+}
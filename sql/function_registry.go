@@ -0,0 +1,28 @@
+package sql
+
+// Function builds an Expression for a function call once its arguments
+// have been resolved. It is the shape every built-in SQL function is
+// registered under in a FunctionRegistry.
+type Function func(args ...Expression) (Expression, error)
+
+// FunctionRegistry is the set of functions the parser's function-call
+// resolution can look up by (lower-cased) name.
+type FunctionRegistry map[string]Function
+
+// NewFunctionRegistry creates an empty FunctionRegistry.
+func NewFunctionRegistry() FunctionRegistry {
+	return make(FunctionRegistry)
+}
+
+// Register adds fn under name, overwriting any previous registration for
+// that name.
+func (r FunctionRegistry) Register(name string, fn Function) {
+	r[name] = fn
+}
+
+// Function looks up a function by name, as the parser does when resolving
+// a function call expression.
+func (r FunctionRegistry) Function(name string) (Function, bool) {
+	fn, ok := r[name]
+	return fn, ok
+}
@@ -43,9 +43,12 @@ func (p *CrossJoin) RowIter() (sql.RowIter, error) {
 		return nil, err
 	}
 
+	ris, _ := ri.(sql.Resettable)
+
 	return &crossJoinIterator{
-		li: li,
-		ri: ri,
+		li:  li,
+		ri:  ri,
+		ris: ris,
 	}, nil
 }
 
@@ -62,17 +65,67 @@ func (p *CrossJoin) TransformExpressionsUp(f func(sql.Expression) sql.Expression
 	)
 }
 
+// crossJoinIterator streams the right child once per left row when it is
+// Resettable, instead of buffering it. If the right child does not
+// implement sql.Resettable, it falls back to buffering its rows the first
+// time through, the same as before.
 type crossJoinIterator struct {
 	li sql.RowIter
 	ri sql.RowIter
 
-	// TODO use a method to reset right iterator in order to not duplicate rows into memory
+	// ris caches the result of asserting ri against sql.Resettable once
+	// (done in CrossJoin.RowIter), instead of re-asserting on every row.
+	// It is nil when ri doesn't implement sql.Resettable.
+	ris sql.Resettable
+
+	// used only in the fallback (non-resettable) path
 	rightRows []sql.Row
 	index     int
 	leftRow   sql.Row
 }
 
 func (i *crossJoinIterator) Next() (sql.Row, error) {
+	if i.ris != nil {
+		return i.nextResettable()
+	}
+
+	return i.nextBuffered()
+}
+
+func (i *crossJoinIterator) nextResettable() (sql.Row, error) {
+	for {
+		if i.leftRow == nil {
+			lr, err := i.li.Next()
+			if err != nil {
+				return nil, err
+			}
+
+			if i.index > 0 {
+				if err := i.ris.Reset(); err != nil {
+					return nil, err
+				}
+			}
+
+			i.index = 0
+			i.leftRow = lr
+		}
+
+		rr, err := i.ri.Next()
+		if err == io.EOF {
+			i.leftRow = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		i.index++
+
+		return append(i.leftRow, rr...), nil
+	}
+}
+
+func (i *crossJoinIterator) nextBuffered() (sql.Row, error) {
 	if len(i.rightRows) == 0 {
 		if err := i.fillRows(); err != io.EOF {
 			return nil, err
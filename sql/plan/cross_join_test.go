@@ -0,0 +1,177 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// sliceRowIter is a minimal, Resettable sql.RowIter backed by a slice, used
+// to test and benchmark the streaming path of crossJoinIterator against
+// the old buffering fallback without needing a real table implementation.
+type sliceRowIter struct {
+	rows   []sql.Row
+	pos    int
+	closed bool
+	resets int
+}
+
+func (i *sliceRowIter) Next() (sql.Row, error) {
+	if i.pos >= len(i.rows) {
+		return nil, io.EOF
+	}
+
+	row := i.rows[i.pos]
+	i.pos++
+	return row, nil
+}
+
+func (i *sliceRowIter) Close() error {
+	i.closed = true
+	return nil
+}
+
+func (i *sliceRowIter) Reset() error {
+	i.pos = 0
+	i.resets++
+	return nil
+}
+
+// nonResettableRowIter wraps sliceRowIter but hides its Reset method, so it
+// forces crossJoinIterator down the legacy buffering path.
+type nonResettableRowIter struct {
+	inner *sliceRowIter
+}
+
+func (i *nonResettableRowIter) Next() (sql.Row, error) { return i.inner.Next() }
+
+func (i *nonResettableRowIter) Close() error { return i.inner.Close() }
+
+func rows(n int) []sql.Row {
+	rs := make([]sql.Row, n)
+	for i := range rs {
+		rs[i] = sql.Row{i}
+	}
+	return rs
+}
+
+func collectAll(it sql.RowIter) ([]sql.Row, error) {
+	var result []sql.Row
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+}
+
+func TestCrossJoinIteratorResettable(t *testing.T) {
+	require := require.New(t)
+
+	left := &sliceRowIter{rows: rows(3)}
+	right := &sliceRowIter{rows: rows(2)}
+
+	it := &crossJoinIterator{li: left, ri: right, ris: right}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Len(got, 6)
+	require.Equal(sql.Row{0, 0}, got[0])
+	require.Equal(sql.Row{0, 1}, got[1])
+	require.Equal(sql.Row{1, 0}, got[2])
+	require.Equal(sql.Row{1, 1}, got[3])
+	require.Equal(sql.Row{2, 0}, got[4])
+	require.Equal(sql.Row{2, 1}, got[5])
+
+	// the right side is reset once per left row after the first.
+	require.Equal(2, right.resets)
+
+	require.NoError(it.Close())
+	require.True(left.closed)
+	require.True(right.closed)
+}
+
+func TestCrossJoinIteratorBuffered(t *testing.T) {
+	require := require.New(t)
+
+	left := &sliceRowIter{rows: rows(3)}
+	right := &nonResettableRowIter{inner: &sliceRowIter{rows: rows(2)}}
+
+	it := &crossJoinIterator{li: left, ri: right}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Len(got, 6)
+	require.Equal(sql.Row{0, 0}, got[0])
+	require.Equal(sql.Row{0, 1}, got[1])
+	require.Equal(sql.Row{1, 0}, got[2])
+	require.Equal(sql.Row{1, 1}, got[3])
+	require.Equal(sql.Row{2, 0}, got[4])
+	require.Equal(sql.Row{2, 1}, got[5])
+
+	require.NoError(it.Close())
+	require.True(left.closed)
+}
+
+func TestCrossJoinIteratorEmptyRightSide(t *testing.T) {
+	require := require.New(t)
+
+	right := &sliceRowIter{rows: rows(0)}
+	it := &crossJoinIterator{
+		li:  &sliceRowIter{rows: rows(3)},
+		ri:  right,
+		ris: right,
+	}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Len(got, 0)
+}
+
+func drain(t *testing.B, it *crossJoinIterator) {
+	t.Helper()
+	for {
+		if _, err := it.Next(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCrossJoinResettable(b *testing.B) {
+	left := rows(100)
+	right := rows(100)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ri := &sliceRowIter{rows: right}
+		it := &crossJoinIterator{
+			li:  &sliceRowIter{rows: left},
+			ri:  ri,
+			ris: ri,
+		}
+		drain(b, it)
+	}
+}
+
+func BenchmarkCrossJoinBuffered(b *testing.B) {
+	left := rows(100)
+	right := rows(100)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		it := &crossJoinIterator{
+			li: &sliceRowIter{rows: left},
+			ri: &nonResettableRowIter{&sliceRowIter{rows: right}},
+		}
+		drain(b, it)
+	}
+}
+
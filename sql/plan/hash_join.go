@@ -0,0 +1,213 @@
+package plan
+
+import (
+	"io"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// HashJoin is an equi-join that builds an in-memory hash table for one side
+// (Build) keyed by BuildKey and probes it with the rows coming from the
+// other side (Probe), keyed by ProbeKey. It is produced by the analyzer as
+// a replacement for an InnerJoin whose condition is a simple equality
+// between a column of each side.
+type HashJoin struct {
+	BinaryNode
+	// Cond is kept for Resolved and TransformExpressionsUp, mirroring the
+	// original InnerJoin this node replaced.
+	Cond sql.Expression
+	// BuildKey and ProbeKey evaluate, respectively, over a row coming from
+	// Left (the build side) and a row coming from Right (the probe side).
+	BuildKey sql.Expression
+	ProbeKey sql.Expression
+}
+
+// NewHashJoin creates a new hash join node. left is the build side, right
+// the probe side.
+func NewHashJoin(left, right sql.Node, cond, buildKey, probeKey sql.Expression) *HashJoin {
+	return &HashJoin{
+		BinaryNode: BinaryNode{
+			Left:  left,
+			Right: right,
+		},
+		Cond:     cond,
+		BuildKey: buildKey,
+		ProbeKey: probeKey,
+	}
+}
+
+// Schema implements the Node interface.
+func (p *HashJoin) Schema() sql.Schema {
+	return append(p.Left.Schema(), p.Right.Schema()...)
+}
+
+// Resolved implements the Resolvable interface.
+func (p *HashJoin) Resolved() bool {
+	return p.Left.Resolved() && p.Right.Resolved() && p.Cond.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (p *HashJoin) RowIter() (sql.RowIter, error) {
+	li, err := p.Left.RowIter()
+	if err != nil {
+		return nil, err
+	}
+
+	ri, err := p.Right.RowIter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashJoinIterator{
+		build:    li,
+		probe:    ri,
+		buildKey: p.BuildKey,
+		probeKey: p.ProbeKey,
+	}, nil
+}
+
+// TransformUp implements the Transformable interface.
+func (p *HashJoin) TransformUp(f func(sql.Node) sql.Node) sql.Node {
+	return f(NewHashJoin(
+		p.Left.TransformUp(f),
+		p.Right.TransformUp(f),
+		p.Cond,
+		p.BuildKey,
+		p.ProbeKey,
+	))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (p *HashJoin) TransformExpressionsUp(f func(sql.Expression) sql.Expression) sql.Node {
+	return NewHashJoin(
+		p.Left.TransformExpressionsUp(f),
+		p.Right.TransformExpressionsUp(f),
+		p.Cond.TransformUp(f),
+		p.BuildKey.TransformUp(f),
+		p.ProbeKey.TransformUp(f),
+	)
+}
+
+// hashJoinIterator builds a map[hash][]sql.Row from the build side on its
+// first call to Next, then streams the probe side, emitting one joined row
+// per match found in the map.
+type hashJoinIterator struct {
+	build sql.RowIter
+	probe sql.RowIter
+
+	buildKey sql.Expression
+	probeKey sql.Expression
+
+	table    map[interface{}][]sql.Row
+	matches  []sql.Row
+	probeRow sql.Row
+}
+
+func (i *hashJoinIterator) Next() (sql.Row, error) {
+	if i.table == nil {
+		if err := i.buildTable(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if len(i.matches) > 0 {
+			row := append(i.matches[0], i.probeRow...)
+			i.matches = i.matches[1:]
+			return row, nil
+		}
+
+		pr, err := i.probe.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := i.probeKey.Eval(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		i.probeRow = pr
+		if key == nil {
+			// SQL equality never matches NULL, including NULL = NULL, so a
+			// NULL probe key can never find a match in the table.
+			i.matches = nil
+			continue
+		}
+		i.matches = i.table[key]
+	}
+}
+
+func (i *hashJoinIterator) buildTable() error {
+	i.table = make(map[interface{}][]sql.Row)
+
+	for {
+		row, err := i.build.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		key, err := i.buildKey.Eval(row)
+		if err != nil {
+			return err
+		}
+
+		if key == nil {
+			// Never index NULL build keys: NULL = NULL must not match, so a
+			// NULL-keyed row can never be a join partner.
+			continue
+		}
+
+		i.table[key] = append(i.table[key], row)
+	}
+}
+
+func (i *hashJoinIterator) Close() error {
+	if err := i.build.Close(); err != nil {
+		_ = i.probe.Close()
+		return err
+	}
+
+	return i.probe.Close()
+}
+
+// isEquiJoinCond reports whether cond is a simple equality between a column
+// of left and a column of right, in either order, the only shape HashJoin
+// currently supports. buildKey is returned as-is (the left side's columns
+// come first in the combined left+right schema, so their indexes already
+// match rows from the build side's own RowIter), but probeKey is rebased
+// to the probe side's own schema, since hashJoinIterator evaluates it against rows coming directly
+// from the right child's RowIter, which are indexed from 0.
+func isEquiJoinCond(cond sql.Expression, left, right sql.Schema) (buildKey, probeKey sql.Expression, ok bool) {
+	eq, isEq := cond.(*expression.Equals)
+	if !isEq {
+		return nil, nil, false
+	}
+
+	leftCol, leftOK := eq.Left().(*expression.GetField)
+	rightCol, rightOK := eq.Right().(*expression.GetField)
+	if !leftOK || !rightOK {
+		return nil, nil, false
+	}
+
+	if leftCol.Index() < len(left) && rightCol.Index() >= len(left) {
+		return leftCol, rebase(rightCol, len(left)), true
+	}
+
+	if rightCol.Index() < len(left) && leftCol.Index() >= len(left) {
+		return rightCol, rebase(leftCol, len(left)), true
+	}
+
+	return nil, nil, false
+}
+
+// rebase returns a GetField identical to col but with its index shifted
+// back by offset, so it can be evaluated against a row indexed by the
+// referenced side's own schema instead of the combined join schema.
+func rebase(col *expression.GetField, offset int) sql.Expression {
+	return expression.NewGetField(col.Index()-offset, col.Type(), col.Name(), col.IsNullable())
+}
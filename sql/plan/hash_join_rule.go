@@ -0,0 +1,46 @@
+package plan
+
+import (
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// OptimizeCrossJoins rewrites joins that are really equi-joins into a
+// HashJoin, so they run as a build-and-probe instead of a Cartesian
+// product plus a row-by-row filter. It is registered in
+// analyzer.DefaultRules and matches two shapes:
+//
+//   - Filter(CrossJoin(a, b), a.x = b.y), the plan a raw `a, b WHERE a.x =
+//     b.y` query produces before any join-specific analysis runs.
+//   - InnerJoin(a, b, a.x = b.y), the plan an explicit `a JOIN b ON a.x =
+//     b.y` query produces.
+//
+// In both cases, the condition must be a simple equality between a column
+// of each side; anything else is left untouched for the nested-loop
+// InnerJoin/Filter+CrossJoin path to execute as-is.
+func OptimizeCrossJoins(node sql.Node) (sql.Node, error) {
+	return node.TransformUp(func(n sql.Node) sql.Node {
+		switch n := n.(type) {
+		case *Filter:
+			cross, ok := n.Child.(*CrossJoin)
+			if !ok {
+				return n
+			}
+
+			buildKey, probeKey, ok := isEquiJoinCond(n.Cond, cross.Left.Schema(), cross.Right.Schema())
+			if !ok {
+				return n
+			}
+
+			return NewHashJoin(cross.Left, cross.Right, n.Cond, buildKey, probeKey)
+		case *InnerJoin:
+			buildKey, probeKey, ok := isEquiJoinCond(n.Cond, n.Left.Schema(), n.Right.Schema())
+			if !ok {
+				return n
+			}
+
+			return NewHashJoin(n.Left, n.Right, n.Cond, buildKey, probeKey)
+		default:
+			return n
+		}
+	}), nil
+}
@@ -0,0 +1,140 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// fakeTable is a minimal sql.Node standing in for a resolved table, just
+// enough to exercise OptimizeCrossJoins without a real table
+// implementation.
+type fakeTable struct {
+	schema sql.Schema
+	rows   []sql.Row
+}
+
+func (t *fakeTable) Schema() sql.Schema { return t.schema }
+func (t *fakeTable) Resolved() bool     { return true }
+func (t *fakeTable) RowIter() (sql.RowIter, error) {
+	return &sliceRowIter{rows: t.rows}, nil
+}
+func (t *fakeTable) TransformUp(f func(sql.Node) sql.Node) sql.Node { return f(t) }
+func (t *fakeTable) TransformExpressionsUp(f func(sql.Expression) sql.Expression) sql.Node {
+	return t
+}
+
+func fakeSchema(col string) sql.Schema {
+	return sql.Schema{&sql.Column{Name: col, Type: sql.Int64}}
+}
+
+// fakeSchema2 returns a two-column schema, used to build tables with
+// left/right width > 1 so a rewritten HashJoin actually has to rebase its
+// probe key instead of accidentally matching at index 0 on both sides.
+func fakeSchema2(col1, col2 string) sql.Schema {
+	return sql.Schema{
+		&sql.Column{Name: col1, Type: sql.Int64},
+		&sql.Column{Name: col2, Type: sql.Int64},
+	}
+}
+
+func TestOptimizeCrossJoinsRewritesFilterOverCrossJoin(t *testing.T) {
+	require := require.New(t)
+
+	left := &fakeTable{schema: fakeSchema("x")}
+	right := &fakeTable{schema: fakeSchema("y")}
+
+	cross := NewCrossJoin(left, right)
+	cond := eqCond(0, 1)
+	filter := NewFilter(cond, cross)
+
+	result, err := OptimizeCrossJoins(filter)
+	require.NoError(err)
+
+	hj, ok := result.(*HashJoin)
+	require.True(ok, "expected *HashJoin, got %T", result)
+	require.Equal(left, hj.Left)
+	require.Equal(right, hj.Right)
+}
+
+// TestOptimizeCrossJoinsRewrittenHashJoinProducesCorrectRows guards against
+// a regression where HashJoin's probe key was left indexed relative to the
+// combined join schema instead of the probe side's own schema: left and
+// right both have width > 1 here, so a probe key that wasn't rebased would
+// either read the wrong column or panic with an index out of range.
+func TestOptimizeCrossJoinsRewrittenHashJoinProducesCorrectRows(t *testing.T) {
+	require := require.New(t)
+
+	left := &fakeTable{
+		schema: fakeSchema2("id", "tag"),
+		rows: []sql.Row{
+			{int64(1), int64(100)},
+			{int64(2), int64(200)},
+			{int64(3), int64(300)},
+		},
+	}
+	right := &fakeTable{
+		schema: fakeSchema2("rid", "id"),
+		rows: []sql.Row{
+			{int64(10), int64(2)},
+			{int64(20), int64(3)},
+			{int64(30), int64(4)},
+		},
+	}
+
+	// left.id (combined index 0) = right.id (combined index 3, i.e. right's
+	// own index 1).
+	cond := eqCond(0, 3)
+	filter := NewFilter(cond, NewCrossJoin(left, right))
+
+	result, err := OptimizeCrossJoins(filter)
+	require.NoError(err)
+
+	hj, ok := result.(*HashJoin)
+	require.True(ok, "expected *HashJoin, got %T", result)
+
+	it, err := hj.RowIter()
+	require.NoError(err)
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Equal([]sql.Row{
+		{int64(2), int64(200), int64(10), int64(2)},
+		{int64(3), int64(300), int64(20), int64(3)},
+	}, got)
+}
+
+func TestOptimizeCrossJoinsRewritesInnerJoin(t *testing.T) {
+	require := require.New(t)
+
+	left := &fakeTable{schema: fakeSchema("x")}
+	right := &fakeTable{schema: fakeSchema("y")}
+
+	join := NewInnerJoin(left, right, eqCond(0, 1))
+
+	result, err := OptimizeCrossJoins(join)
+	require.NoError(err)
+
+	hj, ok := result.(*HashJoin)
+	require.True(ok, "expected *HashJoin, got %T", result)
+	require.Equal(left, hj.Left)
+	require.Equal(right, hj.Right)
+}
+
+func TestOptimizeCrossJoinsLeavesNonEquiJoinsAlone(t *testing.T) {
+	require := require.New(t)
+
+	left := &fakeTable{schema: fakeSchema("x")}
+	right := &fakeTable{schema: fakeSchema("y")}
+
+	cross := NewCrossJoin(left, right)
+	// both sides of the equality are columns of the left table, so this
+	// isn't a join between left and right and the rule must leave the
+	// Filter+CrossJoin plan untouched.
+	filter := NewFilter(eqCond(0, 0), cross)
+
+	result, err := OptimizeCrossJoins(filter)
+	require.NoError(err)
+	require.IsType(&Filter{}, result)
+}
@@ -0,0 +1,55 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestHashJoinIterator(t *testing.T) {
+	require := require.New(t)
+
+	it := &hashJoinIterator{
+		build:    &sliceRowIter{rows: int64Rows(1, 2, 3)},
+		probe:    &sliceRowIter{rows: int64Rows(2, 3, 4)},
+		buildKey: expressionAt(0),
+		probeKey: expressionAt(0),
+	}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Len(got, 2)
+	require.Contains(got, sql.Row{int64(2), int64(2)})
+	require.Contains(got, sql.Row{int64(3), int64(3)})
+}
+
+// expressionAt returns a GetField reading column i out of a one-column row,
+// used to build the join keys in tests.
+func expressionAt(i int) sql.Expression {
+	return expression.NewGetField(i, sql.Int64, "key", false)
+}
+
+func TestHashJoinIteratorSkipsNullKeys(t *testing.T) {
+	require := require.New(t)
+
+	it := &hashJoinIterator{
+		build: &sliceRowIter{rows: []sql.Row{
+			{int64(1)},
+			{nil},
+		}},
+		probe: &sliceRowIter{rows: []sql.Row{
+			{int64(1)},
+			{nil},
+		}},
+		buildKey: expressionAt(0),
+		probeKey: expressionAt(0),
+	}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	// NULL = NULL must not match, even though both sides hash to the Go
+	// nil key.
+	require.Equal([]sql.Row{{int64(1), int64(1)}}, got)
+}
@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// InnerJoin is an inner join between two tables, evaluated with a nested
+// loop that discards rows not matching Cond. It is the node the analyzer
+// starts from before rules such as the hash join conversion get a chance
+// to pick a faster execution strategy.
+type InnerJoin struct {
+	BinaryNode
+	Cond sql.Expression
+}
+
+// NewInnerJoin creates a new inner join node from two tables.
+func NewInnerJoin(left sql.Node, right sql.Node, cond sql.Expression) *InnerJoin {
+	return &InnerJoin{
+		BinaryNode: BinaryNode{
+			Left:  left,
+			Right: right,
+		},
+		Cond: cond,
+	}
+}
+
+// Schema implements the Node interface.
+func (p *InnerJoin) Schema() sql.Schema {
+	return append(p.Left.Schema(), p.Right.Schema()...)
+}
+
+// Resolved implements the Resolvable interface.
+func (p *InnerJoin) Resolved() bool {
+	return p.Left.Resolved() && p.Right.Resolved() && p.Cond.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (p *InnerJoin) RowIter() (sql.RowIter, error) {
+	li, err := p.Left.RowIter()
+	if err != nil {
+		return nil, err
+	}
+
+	ri, err := p.Right.RowIter()
+	if err != nil {
+		return nil, err
+	}
+
+	return &innerJoinIterator{
+		l:    li,
+		r:    ri,
+		cond: p.Cond,
+	}, nil
+}
+
+// TransformUp implements the Transformable interface.
+func (p *InnerJoin) TransformUp(f func(sql.Node) sql.Node) sql.Node {
+	return f(NewInnerJoin(p.Left.TransformUp(f), p.Right.TransformUp(f), p.Cond))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (p *InnerJoin) TransformExpressionsUp(f func(sql.Expression) sql.Expression) sql.Node {
+	return NewInnerJoin(
+		p.Left.TransformExpressionsUp(f),
+		p.Right.TransformExpressionsUp(f),
+		p.Cond.TransformUp(f),
+	)
+}
+
+// innerJoinIterator wraps a crossJoinIterator and filters out rows that do
+// not satisfy cond, avoiding an extra Filter node on top of a CrossJoin.
+type innerJoinIterator struct {
+	l    sql.RowIter
+	r    sql.RowIter
+	cond sql.Expression
+
+	cross *crossJoinIterator
+}
+
+func (i *innerJoinIterator) Next() (sql.Row, error) {
+	if i.cross == nil {
+		ris, _ := i.r.(sql.Resettable)
+		i.cross = &crossJoinIterator{li: i.l, ri: i.r, ris: ris}
+	}
+
+	for {
+		row, err := i.cross.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := i.cond.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches, ok := v.(bool); ok && matches {
+			return row, nil
+		}
+	}
+}
+
+func (i *innerJoinIterator) Close() error {
+	if i.cross != nil {
+		return i.cross.Close()
+	}
+
+	if err := i.l.Close(); err != nil {
+		_ = i.r.Close()
+		return err
+	}
+
+	return i.r.Close()
+}
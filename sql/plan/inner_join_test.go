@@ -0,0 +1,58 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// eqCond builds `row[left] = row[right]` over a two-table join, where
+// right is the index of the column within the combined row (i.e. already
+// offset by the width of the left side).
+func eqCond(left, right int) sql.Expression {
+	return expression.NewEquals(
+		expression.NewGetField(left, sql.Int64, "left", false),
+		expression.NewGetField(right, sql.Int64, "right", false),
+	)
+}
+
+func int64Rows(vals ...int64) []sql.Row {
+	rs := make([]sql.Row, len(vals))
+	for i, v := range vals {
+		rs[i] = sql.Row{v}
+	}
+	return rs
+}
+
+func TestInnerJoinIterator(t *testing.T) {
+	require := require.New(t)
+
+	it := &innerJoinIterator{
+		l:    &sliceRowIter{rows: int64Rows(1, 2, 3)},
+		r:    &sliceRowIter{rows: int64Rows(2, 3, 4)},
+		cond: eqCond(0, 1),
+	}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Equal([]sql.Row{
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+	}, got)
+}
+
+func TestInnerJoinIteratorNoMatches(t *testing.T) {
+	require := require.New(t)
+
+	it := &innerJoinIterator{
+		l:    &sliceRowIter{rows: int64Rows(1, 2)},
+		r:    &sliceRowIter{rows: int64Rows(3, 4)},
+		cond: eqCond(0, 1),
+	}
+
+	got, err := collectAll(it)
+	require.NoError(err)
+	require.Len(got, 0)
+}
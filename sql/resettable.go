@@ -0,0 +1,12 @@
+package sql
+
+// Resettable is an optional interface that a RowIter can implement when it
+// is able to start over from the beginning without being recreated. Nodes
+// that need to iterate over the same set of rows more than once (for
+// example, the right-hand side of a nested loop join) should use this to
+// avoid buffering rows in memory.
+type Resettable interface {
+	// Reset restarts the iterator so that the next call to Next returns the
+	// first row again.
+	Reset() error
+}